@@ -0,0 +1,75 @@
+// Copyright 2020 Walter Scheper
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command convcom-lint is an example commit-msg hook driver: install it as
+// .git/hooks/commit-msg to lint every commit message against the rules in
+// .convcom.yaml before the commit is created.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	commit "github.com/wfscheper/convcom"
+	"github.com/wfscheper/convcom/lint"
+)
+
+func main() {
+	rulesPath := flag.String("rules", ".convcom.yaml", "path to a lint rules file")
+	flag.Parse()
+
+	if err := run(*rulesPath, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "convcom-lint:", err)
+		os.Exit(1)
+	}
+}
+
+func run(rulesPath string, args []string) error {
+	if 1 != len(args) {
+		return errors.New("usage: convcom-lint [-rules path] <commit-msg-file>")
+	}
+
+	message, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("cannot read commit message: %w", err)
+	}
+
+	rules, err := lint.LoadRulesFile(rulesPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot load lint rules: %w", err)
+	}
+
+	parser, err := commit.New(&commit.Config{})
+	if err != nil {
+		return fmt.Errorf("cannot build parser: %w", err)
+	}
+	c, err := parser.Parse(string(message))
+	if err != nil {
+		return fmt.Errorf("cannot parse commit message: %w", err)
+	}
+
+	var failed bool
+	for _, issue := range lint.New(rules).Check(c) {
+		fmt.Fprintf(os.Stderr, "%s:%d:%d: %s: %s [%s]\n", args[0], issue.Line, issue.Char, issue.Severity, issue.Message, issue.RuleID)
+		if lint.SeverityError == issue.Severity {
+			failed = true
+		}
+	}
+	if failed {
+		return errors.New("commit message failed lint checks")
+	}
+	return nil
+}
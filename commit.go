@@ -15,7 +15,6 @@
 package commit
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -33,6 +32,10 @@ type Commit struct {
 	Header string
 	// MergeHeader is merge header if any.
 	MergeHeader string
+	// Source is the branch a merge commit merged from, if any.
+	Source string
+	// Branch is the branch a merge commit merged into, if any.
+	Branch string
 	// Body is a free-from long description of the changes in the commit.
 	Body string
 	// Footers are token/value pairs at the end of a commit.
@@ -98,6 +101,12 @@ type Config struct {
 	// ErrorCallback is a function to call when a commit cannot be parsed. If nil,
 	// then the parser will return an error.
 	ErrorCallback func(message string, line, char int) error
+	// StrictReferences requires that every "<action> <prefix><issue>"
+	// reference in the body or footers use one of ReferenceActions. Any
+	// other action is reported as a ParseError.
+	//
+	// Default: false
+	StrictReferences bool
 }
 
 var (
@@ -107,6 +116,17 @@ var (
 	fieldPattern     = regexp.MustCompile(`^-(.*?)-$`)
 	revertPattern    = regexp.MustCompile(`^Revert\s"([\s\S]*)"\s*This reverts commit (\w*)\.`)
 	revertGropus     = []string{"header", "hash"}
+
+	// footerPattern matches a single footer line, eg. "Reviewed-by: Z" or
+	// "Refs #133". The token is either one or more letters/hyphens, or the
+	// literal "BREAKING CHANGE".
+	footerPattern = regexp.MustCompile(`^(?:([A-Za-z-]+)|(BREAKING CHANGE))(?:: | #)(.*)$`)
+	// malformedFooterPattern matches a candidate footer line whose token
+	// contains internal whitespace, which footerPattern disallows: tokens
+	// must be hyphenated words, or the literal "BREAKING CHANGE".
+	malformedFooterPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]*(?: [A-Za-z0-9]+)+): (.*)$`)
+	// mentionPattern matches an @mention in free-form text.
+	mentionPattern = regexp.MustCompile(`@([\w-]+)`)
 )
 
 // Parser is a commit parser
@@ -155,31 +175,369 @@ func New(cfg *Config) (p *Parser, err error) {
 
 // Parse parses a commit message and returns a Commit.
 //
-// If the commit message does not match the specification,
-// then an empty Commit will be returned
-// along with an error describing the parse error.
+// If the commit message does not match the specification, then a nil
+// Commit is returned along with a ParseErrors describing every problem
+// found in a single pass over the message: illegal header characters,
+// a missing type or description, malformed footers, and, if
+// cfg.StrictReferences is set, unknown reference actions.
 func (p *Parser) Parse(s string) (c *Commit, err error) {
+	if "" != p.cfg.CommentCharacter {
+		s = stripComments(s, p.cfg.CommentCharacter)
+	}
+
+	if nil != p.cfg.revertPattern {
+		if m := p.cfg.revertPattern.FindStringSubmatch(s); nil != m {
+			return &Commit{Reverts: zipGroups(p.cfg.RevertGroups, m[1:])}, nil
+		}
+	}
+
 	// split message into lines
 	lines := strings.Split(s, "\n")
 	header := lines[0]
 
+	isMerge := false
+	var mergeGroups map[string]string
+	if nil != p.cfg.mergePattern {
+		if m := p.cfg.mergePattern.FindStringSubmatch(header); nil != m {
+			isMerge = true
+			mergeGroups = zipGroups(p.cfg.MergeGroups, m[1:])
+		}
+	}
+
+	var errs ParseErrors
+
+	if isMerge {
+		c = &Commit{MergeHeader: header, Source: mergeGroups["source"], Branch: mergeGroups["branch"]}
+		if h, ok := mergeGroups["header"]; ok {
+			hc, herr := p.parseHeader(h, 1)
+			if herr != nil {
+				errs = append(errs, herr.(ParseErrors)...)
+			} else {
+				c = hc
+				c.MergeHeader = header
+				c.Source = mergeGroups["source"]
+				c.Branch = mergeGroups["branch"]
+			}
+		}
+		errs = append(errs, p.parseBody(c, lines[1:], 2)...)
+		if len(errs) > 0 {
+			return nil, errs
+		}
+		return c, nil
+	}
+
 	// parse header
-	c, err = p.parseHeader(header, 1)
-	if err != nil {
-		return nil, err
+	c, herr := p.parseHeader(header, 1)
+	if herr != nil {
+		errs = append(errs, herr.(ParseErrors)...)
+		c = &Commit{}
 	}
 	switch {
 	case "" == c.Type:
-		return nil, errors.New("commit header must contain a type")
+		errs = append(errs, ParseError{Line: 1, Char: 0, Message: "commit header must contain a type"})
 	case "" == c.Description:
-		return nil, errors.New("commit header must contain a description")
+		errs = append(errs, ParseError{Line: 1, Char: len(header), Message: "commit header must contain a description"})
+	}
+
+	errs = append(errs, p.parseBody(c, lines[1:], 2)...)
+
+	if len(errs) > 0 {
+		return nil, errs
 	}
 	return c, nil
 }
 
+// zipGroups pairs names with values positionally, dropping any names past
+// the end of values.
+func zipGroups(names, values []string) map[string]string {
+	m := make(map[string]string, len(names))
+	for i, name := range names {
+		if i < len(values) {
+			m[name] = values[i]
+		}
+	}
+	return m
+}
+
+// stripComments removes any line beginning with comment from s.
+func stripComments(s, comment string) string {
+	lines := strings.Split(s, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.HasPrefix(line, comment) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// parseBody parses the body, footers, notes, mentions, and references of a
+// commit from the lines following the header, and reports any malformed
+// footers or, if cfg.StrictReferences is set, unknown reference actions.
+// lineOffset is the absolute line number of lines[0], for positioning
+// errors.
+func (p *Parser) parseBody(c *Commit, lines []string, lineOffset int) ParseErrors {
+	// a single blank line separates the header from the body
+	if len(lines) > 0 && "" == lines[0] {
+		lines = lines[1:]
+		lineOffset++
+	}
+
+	var errs ParseErrors
+	var bodyLines, footerLines []string
+
+	// takeFooterBlock reports whether the contiguous run of non-blank lines
+	// starting at start looks like a footer block, ie. its first line
+	// looks like a footer. If so, it appends the block to footerLines and
+	// returns its length, so the caller can skip past it; otherwise it
+	// reports a malformed footer token, if any, and returns 0, leaving the
+	// block to be treated as body.
+	takeFooterBlock := func(start int) int {
+		block := footerLookaheadBlock(lines, start)
+		if len(block) == 0 {
+			return 0
+		}
+		if isFooterBlock(block) {
+			footerLines = append(footerLines, block...)
+			return len(block)
+		}
+		if m := malformedFooterPattern.FindStringSubmatch(block[0]); nil != m && !strings.EqualFold(m[1], "BREAKING CHANGE") {
+			errs = append(errs, ParseError{
+				Line:    lineOffset + start,
+				Char:    strings.Index(block[0], ":"),
+				Message: fmt.Sprintf("footer token %q must not contain whitespace", m[1]),
+			})
+		}
+		return 0
+	}
+
+	// the lines immediately after the header, with no intervening body,
+	// are a candidate footer block too: the commit has no body at all,
+	// eg. "header\n\nBREAKING CHANGE: ..." or "header\n\nCloses #1".
+	i := takeFooterBlock(0)
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if "" != line {
+			bodyLines = append(bodyLines, line)
+			continue
+		}
+		// a blank line always ends footer continuation; only a
+		// genuine footer-shaped block following it re-enters footer mode,
+		// so prose after a footer isn't folded into the last footer value.
+		if n := takeFooterBlock(i + 1); n > 0 {
+			i += n
+			continue
+		}
+		bodyLines = append(bodyLines, line)
+	}
+
+	c.Body = strings.TrimSpace(strings.Join(bodyLines, "\n"))
+	rawFooters := strings.Join(footerLines, "\n")
+	p.parseFooters(c, footerLines)
+
+	c.Mentions = dedupeStrings(append(extractMentions(c.Body), extractMentions(rawFooters)...))
+	c.References = append(p.parseReferences(c.Body), p.parseReferences(rawFooters)...)
+
+	if p.cfg.StrictReferences {
+		errs = append(errs, p.checkReferenceActions(lines, lineOffset)...)
+	}
+
+	return errs
+}
+
+// isFooterBlock reports whether block, a run of lines bounded by blank
+// lines, begins a footer: the first line must look like a footer line,
+// everything after it is treated as a continuation of that footer's value.
+func isFooterBlock(block []string) bool {
+	return footerPattern.MatchString(block[0])
+}
+
+// footerLookaheadBlock returns the contiguous run of non-blank lines in
+// lines starting at start, ie. the candidate footer block that follows a
+// blank line (or, at start == 0, the header's separator blank).
+func footerLookaheadBlock(lines []string, start int) []string {
+	var block []string
+	for start < len(lines) && "" != lines[start] {
+		block = append(block, lines[start])
+		start++
+	}
+	return block
+}
+
+// parseFooters parses lines, a block of footer lines, into c.Footers,
+// promoting any footer whose token matches cfg.NoteKeywords to c.Notes and
+// marking the commit as breaking.
+func (p *Parser) parseFooters(c *Commit, lines []string) {
+	var footers []Footer
+	for _, line := range lines {
+		if m := footerPattern.FindStringSubmatch(line); nil != m {
+			token := m[1]
+			if "" == token {
+				token = m[2]
+			}
+			footers = append(footers, Footer{Token: token, Value: m[3]})
+			continue
+		}
+		// a continuation line belongs to the previous footer's value
+		if len(footers) > 0 {
+			last := &footers[len(footers)-1]
+			last.Value = strings.TrimSpace(last.Value + "\n" + line)
+		}
+	}
+
+	for _, f := range footers {
+		if p.isNoteKeyword(f.Token) {
+			c.IsBreaking = true
+			if nil == c.Notes {
+				c.Notes = Notes{}
+			}
+			c.Notes[f.Token] = f.Value
+		}
+	}
+	c.Footers = footers
+}
+
+// isNoteKeyword reports whether token matches one of cfg.NoteKeywords,
+// normalizing the "BREAKING-CHANGE" trailer token to "BREAKING CHANGE" so
+// it matches the default keyword.
+func (p *Parser) isNoteKeyword(token string) bool {
+	if strings.EqualFold(token, "BREAKING-CHANGE") {
+		token = "BREAKING CHANGE"
+	}
+	for _, kw := range p.cfg.NoteKeywords {
+		if strings.EqualFold(token, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractMentions returns the @mentions found in s, in the order they
+// appear.
+func extractMentions(s string) []string {
+	var mentions []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(s, -1) {
+		mentions = append(mentions, m[1])
+	}
+	return mentions
+}
+
+// dedupeStrings returns ss with duplicate values removed, preserving the
+// order of first occurrence.
+func dedupeStrings(ss []string) []string {
+	if nil == ss {
+		return nil
+	}
+	seen := make(map[string]bool, len(ss))
+	out := make([]string, 0, len(ss))
+	for _, s := range ss {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	return out
+}
+
+// parseReferences scans s for "<action> <prefix><issue>" pairs, using
+// cfg.ReferenceActions and cfg.IssuePrefixes to build the search pattern.
+func (p *Parser) parseReferences(s string) References {
+	re := p.referencePattern()
+	var refs References
+	for _, m := range re.FindAllStringSubmatch(s, -1) {
+		refs = append(refs, Reference{
+			"action": m[1],
+			"prefix": m[2],
+			"issue":  m[3],
+		})
+	}
+	return refs
+}
+
+// referencePattern builds the regular expression used to find issue
+// references, honoring cfg.IssuePrefixesCaseSensitive.
+func (p *Parser) referencePattern() *regexp.Regexp {
+	actions := make([]string, len(p.cfg.ReferenceActions))
+	for i, a := range p.cfg.ReferenceActions {
+		actions[i] = regexp.QuoteMeta(a)
+	}
+	prefixes := make([]string, len(p.cfg.IssuePrefixes))
+	for i, pfx := range p.cfg.IssuePrefixes {
+		prefixes[i] = regexp.QuoteMeta(pfx)
+	}
+
+	prefixGroup := fmt.Sprintf("(%s)", strings.Join(prefixes, "|"))
+	if !p.cfg.IssuePrefixesCaseSensitive {
+		prefixGroup = "(?i:" + prefixGroup + ")"
+	}
+
+	pattern := fmt.Sprintf(`(?i:(%s))\s+%s(\w+)`, strings.Join(actions, "|"), prefixGroup)
+	return regexp.MustCompile(pattern)
+}
+
+// checkReferenceActions reports any "<action> <prefix><issue>" reference
+// in lines whose action isn't one of cfg.ReferenceActions. lineOffset is
+// the absolute line number of lines[0].
+func (p *Parser) checkReferenceActions(lines []string, lineOffset int) ParseErrors {
+	re := p.anyActionPattern()
+	var errs ParseErrors
+	for i, line := range lines {
+		for _, m := range re.FindAllStringSubmatchIndex(line, -1) {
+			action := line[m[2]:m[3]]
+			if !p.isReferenceAction(action) {
+				errs = append(errs, ParseError{
+					Line:    lineOffset + i,
+					Char:    m[2],
+					Message: fmt.Sprintf("unknown reference action %q", action),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// isReferenceAction reports whether action matches one of
+// cfg.ReferenceActions, case insensitive.
+func (p *Parser) isReferenceAction(action string) bool {
+	for _, a := range p.cfg.ReferenceActions {
+		if strings.EqualFold(action, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyActionPattern is like referencePattern, but accepts any leading word
+// as the action, so checkReferenceActions can flag actions that aren't
+// configured.
+func (p *Parser) anyActionPattern() *regexp.Regexp {
+	prefixes := make([]string, len(p.cfg.IssuePrefixes))
+	for i, pfx := range p.cfg.IssuePrefixes {
+		prefixes[i] = regexp.QuoteMeta(pfx)
+	}
+
+	prefixGroup := fmt.Sprintf("(%s)", strings.Join(prefixes, "|"))
+	if !p.cfg.IssuePrefixesCaseSensitive {
+		prefixGroup = "(?i:" + prefixGroup + ")"
+	}
+
+	pattern := fmt.Sprintf(`(\w+)\s+%s\w+`, prefixGroup)
+	return regexp.MustCompile(pattern)
+}
+
+// parseHeader parses h, the header line of a commit, accumulating every
+// illegal character it finds into a ParseErrors instead of stopping at
+// the first one. Once the header reaches a point where it can no longer
+// be meaningfully recovered from (a malformed colon, or text trailing an
+// already-closed scope), it stops scanning and returns the errors found
+// so far.
 func (p *Parser) parseHeader(h string, line int) (*Commit, error) {
 	var inDescription, inScope bool
 	var b strings.Builder
+	var errs ParseErrors
 	commit := &Commit{}
 	for i, c := range h {
 		if inDescription {
@@ -191,32 +549,45 @@ func (p *Parser) parseHeader(h string, line int) (*Commit, error) {
 		}
 		// still parsing the type/description
 		switch c {
+		case '!':
+			// a breaking change marker must immediately precede the colon
+			if i+1 >= len(h) || ':' != h[i+1] {
+				errs = append(errs, ParseError{
+					Char:    i,
+					Line:    line,
+					Message: "illegal '!' character, breaking change marker must be followed by a colon",
+				})
+				continue
+			}
+			commit.IsBreaking = true
 		case '(':
 			if inScope {
-				return nil, ParseError{
+				errs = append(errs, ParseError{
 					Char:    i,
 					Line:    line,
 					Message: "illegal '(' character in scope",
-				}
+				})
+				continue
 			}
 			inScope = true
 			commit.Type = b.String()
 			if "" == commit.Type {
-				return nil, ParseError{
+				errs = append(errs, ParseError{
 					Char:    i,
 					Line:    line,
 					Message: "illegal '(' character in type",
-				}
+				})
 			}
 			b.Reset()
 		case ')':
 			// if scope hasn't started this is an illegal character
 			if !inScope {
-				return nil, ParseError{
+				errs = append(errs, ParseError{
 					Char:    i,
 					Line:    line,
 					Message: "illegal ')' character in type",
-				}
+				})
+				continue
 			}
 			// done with scope
 			commit.Scope = b.String()
@@ -236,11 +607,12 @@ func (p *Parser) parseHeader(h string, line int) (*Commit, error) {
 				char = i + 2
 			}
 			if 0 != char {
-				return nil, ParseError{
+				errs = append(errs, ParseError{
 					Char:    char,
 					Line:    line,
 					Message: fmtError("commit %s must be followed by a colon and a single space", inScope),
-				}
+				})
+				return nil, errs
 			}
 			if !inScope {
 				// no scope, just type
@@ -249,20 +621,20 @@ func (p *Parser) parseHeader(h string, line int) (*Commit, error) {
 			}
 			inDescription = true
 		case ' ':
-			err := ParseError{
+			errs = append(errs, ParseError{
 				Char:    i,
 				Line:    line,
 				Message: fmtError("illegal ' ' character in %s", inScope),
-			}
-			return nil, err
+			})
 		default:
 			if "" != commit.Scope {
 				// we finished scope but didn't hit a ':' above
-				return nil, ParseError{
+				errs = append(errs, ParseError{
 					Char:    i,
 					Line:    line,
 					Message: "commit scope must be followed by a colon and a single space",
-				}
+				})
+				return nil, errs
 			}
 			if _, err := b.WriteRune(c); err != nil {
 				return nil, fmt.Errorf("could not write character '%c' to internal buffer: %w", c, err)
@@ -271,11 +643,22 @@ func (p *Parser) parseHeader(h string, line int) (*Commit, error) {
 	}
 	if !inDescription {
 		// never entered the description
-		return nil, ParseError{
-			Char:    len(h) - 1,
-			Line:    line,
-			Message: fmtError("commit %s must be followed by a colon and a single space", inScope),
+		if len(errs) == 0 {
+			char := len(h) - 1
+			if char < 0 {
+				// an empty header has no character to point at
+				char = 0
+			}
+			errs = append(errs, ParseError{
+				Char:    char,
+				Line:    line,
+				Message: fmtError("commit %s must be followed by a colon and a single space", inScope),
+			})
 		}
+		return nil, errs
+	}
+	if len(errs) > 0 {
+		return nil, errs
 	}
 	commit.Description = strings.TrimSpace(b.String())
 	return commit, nil
@@ -298,6 +681,55 @@ func (p ParseError) Error() string {
 	return fmt.Sprintf("%s:%d col %d", p.Message, p.Line, p.Char)
 }
 
+// Format renders p as a single diagnostic excerpt from src, the original
+// commit message, eg.
+//
+//	1:8: illegal '(' character in scope
+//	feat(api(: add thing
+//	        ^
+func (p ParseError) Format(src string) string {
+	lines := strings.Split(src, "\n")
+	var lineText string
+	if p.Line >= 1 && p.Line <= len(lines) {
+		lineText = lines[p.Line-1]
+	}
+	col := p.Char
+	if col < 0 {
+		col = 0
+	}
+	caret := strings.Repeat(" ", col) + "^"
+	return fmt.Sprintf("%d:%d: %s\n%s\n%s", p.Line, p.Char, p.Message, lineText, caret)
+}
+
+// ParseErrors is every problem found parsing a commit message. It
+// implements both error and Unwrap() []error, so callers can use
+// errors.Is/errors.As against any one of the underlying ParseErrors.
+type ParseErrors []ParseError
+
+func (pe ParseErrors) Error() string {
+	switch len(pe) {
+	case 0:
+		return ""
+	case 1:
+		return pe[0].Error()
+	}
+	msgs := make([]string, len(pe))
+	for i, e := range pe {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual ParseErrors that make up pe, per the Go
+// 1.20 multi-error convention.
+func (pe ParseErrors) Unwrap() []error {
+	errs := make([]error, len(pe))
+	for i, e := range pe {
+		errs[i] = e
+	}
+	return errs
+}
+
 type Footer struct {
 	Token string
 	Value string
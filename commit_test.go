@@ -1,6 +1,7 @@
 package commit
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -33,6 +34,11 @@ func Test_parseHeader(t *testing.T) {
 		{"type: description", &Commit{Type: "type", Description: "description"}, ""},
 		{"type:", nil, "commit type must be followed by a colon and a single space:1 col 4"},
 		{"type:description", nil, "commit type must be followed by a colon and a single space:1 col 5"},
+		{"type!: description", &Commit{Type: "type", Description: "description", IsBreaking: true}, ""},
+		{"type(scope)!: description", &Commit{Type: "type", Scope: "scope", Description: "description", IsBreaking: true}, ""},
+		{"type!scope: description", nil, "illegal '!' character, breaking change marker must be followed by a colon:1 col 4"},
+		{"type!", nil, "illegal '!' character, breaking change marker must be followed by a colon:1 col 4"},
+		{"", nil, "commit type must be followed by a colon and a single space:1 col 0"},
 	}
 	p, err := New(&Config{})
 	if err != nil {
@@ -58,9 +64,9 @@ func TestParse(t *testing.T) {
 		want      *Commit
 		wantError string
 	}{
-		{"type: ", nil, "commit header must contain a description"},
+		{"type: ", nil, "commit header must contain a description:1 col 6"},
 		{"type: description", &Commit{Type: "type", Description: "description"}, ""},
-		{"type(scope): ", nil, "commit header must contain a description"},
+		{"type(scope): ", nil, "commit header must contain a description:1 col 13"},
 		{"type(scope): description", &Commit{Type: "type", Scope: "scope", Description: "description"}, ""},
 	}
 
@@ -81,3 +87,355 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_body(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *Commit
+	}{
+		{
+			name:  "multi-paragraph body",
+			input: "feat: add x\n\nFirst paragraph.\n\nSecond paragraph.",
+			want: &Commit{
+				Type:        "feat",
+				Description: "add x",
+				Body:        "First paragraph.\n\nSecond paragraph.",
+			},
+		},
+		{
+			name:  "multiple footers",
+			input: "fix: correct bug\n\nThis fixes a thing.\n\nReviewed-by: Z\nRefs #133",
+			want: &Commit{
+				Type:        "fix",
+				Description: "correct bug",
+				Body:        "This fixes a thing.",
+				Footers: []Footer{
+					{Token: "Reviewed-by", Value: "Z"},
+					{Token: "Refs", Value: "133"},
+				},
+			},
+		},
+		{
+			name:  "footer continuation line",
+			input: "fix: continuation\n\nThis fixes a thing.\n\nBREAKING CHANGE: the first line\n  second line of the note continues here",
+			want: &Commit{
+				Type:        "fix",
+				Description: "continuation",
+				Body:        "This fixes a thing.",
+				Footers: []Footer{
+					{Token: "BREAKING CHANGE", Value: "the first line\n  second line of the note continues here"},
+				},
+				Notes:      Notes{"BREAKING CHANGE": "the first line\n  second line of the note continues here"},
+				IsBreaking: true,
+			},
+		},
+		{
+			name:  "! breaking marker and BREAKING CHANGE footer",
+			input: "feat(api)!: drop old endpoint\n\nThis removes the deprecated endpoint.\n\nBREAKING CHANGE: the /v1 endpoint is gone.",
+			want: &Commit{
+				Type:        "feat",
+				Scope:       "api",
+				Description: "drop old endpoint",
+				Body:        "This removes the deprecated endpoint.",
+				Footers: []Footer{
+					{Token: "BREAKING CHANGE", Value: "the /v1 endpoint is gone."},
+				},
+				Notes:      Notes{"BREAKING CHANGE": "the /v1 endpoint is gone."},
+				IsBreaking: true,
+			},
+		},
+		{
+			name:  "no body, breaking change footer only",
+			input: "feat: allow config extends\n\nBREAKING CHANGE: extends key now used",
+			want: &Commit{
+				Type:        "feat",
+				Description: "allow config extends",
+				Footers: []Footer{
+					{Token: "BREAKING CHANGE", Value: "extends key now used"},
+				},
+				Notes:      Notes{"BREAKING CHANGE": "extends key now used"},
+				IsBreaking: true,
+			},
+		},
+		{
+			name:  "no body, plain footer only",
+			input: "fix: patch\n\nCloses #1",
+			want: &Commit{
+				Type:        "fix",
+				Description: "patch",
+				Footers: []Footer{
+					{Token: "Closes", Value: "1"},
+				},
+				References: References{
+					{"action": "Closes", "prefix": "#", "issue": "1"},
+				},
+			},
+		},
+		{
+			name:  "mentions and mixed case reference actions",
+			input: "fix: patch\n\nthis patch fixes #11, thanks @alice\n\nCloses #22\nReviewed-by: @bob",
+			want: &Commit{
+				Type:        "fix",
+				Description: "patch",
+				Body:        "this patch fixes #11, thanks @alice",
+				Footers: []Footer{
+					{Token: "Closes", Value: "22"},
+					{Token: "Reviewed-by", Value: "@bob"},
+				},
+				Mentions: []string{"alice", "bob"},
+				References: References{
+					{"action": "fixes", "prefix": "#", "issue": "11"},
+					{"action": "Closes", "prefix": "#", "issue": "22"},
+				},
+			},
+		},
+		{
+			name:  "body prose after a footer",
+			input: "fix: x\n\nFirst para.\n\nCloses #1\n\nTrailing prose after footer.",
+			want: &Commit{
+				Type:        "fix",
+				Description: "x",
+				Body:        "First para.\n\nTrailing prose after footer.",
+				Footers: []Footer{
+					{Token: "Closes", Value: "1"},
+				},
+				References: References{
+					{"action": "Closes", "prefix": "#", "issue": "1"},
+				},
+			},
+		},
+		{
+			name:  "leading paragraph whose first line looks like a footer is parsed as footers",
+			input: "fix: x\n\nNote: some context\n\nMore body here.",
+			want: &Commit{
+				Type:        "fix",
+				Description: "x",
+				Body:        "More body here.",
+				Footers: []Footer{
+					{Token: "Note", Value: "some context"},
+				},
+			},
+		},
+	}
+
+	p, err := New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Parallel()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit, err := p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("p.Parse(%q) returned an unexpected error: %v", tt.input, err)
+			}
+			if got, want := commit, tt.want; !reflect.DeepEqual(got, want) {
+				t.Errorf("p.Parse(%q) returned %#v, want %#v", tt.input, got, want)
+			}
+		})
+	}
+}
+
+func TestParse_commentCharacter(t *testing.T) {
+	p, err := New(&Config{CommentCharacter: "#"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "chore: strip comments\n# this line is a comment and is discarded\n\nbody text\n# another comment\nmore body"
+	want := &Commit{
+		Type:        "chore",
+		Description: "strip comments",
+		Body:        "body text\nmore body",
+	}
+
+	commit, err := p.Parse(input)
+	if err != nil {
+		t.Fatalf("p.Parse(%q) returned an unexpected error: %v", input, err)
+	}
+	if got := commit; !reflect.DeepEqual(got, want) {
+		t.Errorf("p.Parse(%q) returned %#v, want %#v", input, got, want)
+	}
+}
+
+func TestParse_merge(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   *Config
+		input string
+		want  *Commit
+	}{
+		{
+			name: "github merge header",
+			cfg: &Config{
+				MergePattern: `^Merge pull request #(\d+) from (.*)$`,
+				MergeGroups:  []string{"id", "source"},
+			},
+			input: "Merge pull request #27 from wfscheper/feature-branch\n\nThis merges the new feature.",
+			want: &Commit{
+				MergeHeader: "Merge pull request #27 from wfscheper/feature-branch",
+				Source:      "wfscheper/feature-branch",
+				Body:        "This merges the new feature.",
+			},
+		},
+		{
+			name: "gitlab merge header",
+			cfg: &Config{
+				MergePattern: `^Merge branch '[^']+' into '([^']+)'$`,
+				MergeGroups:  []string{"branch"},
+			},
+			input: "Merge branch 'feature' into 'master'\n\n* feature:\n  some change",
+			want: &Commit{
+				MergeHeader: "Merge branch 'feature' into 'master'",
+				Branch:      "master",
+				Body:        "* feature:\n  some change",
+			},
+		},
+		{
+			name: "merge header with embedded conventional header",
+			cfg: &Config{
+				MergePattern: `^Merge pull request #(\d+) from (\S+): (.*)$`,
+				MergeGroups:  []string{"id", "source", "header"},
+			},
+			input: "Merge pull request #27 from wfscheper/feature-branch: feat(api)!: add thing\n\nThis squashes the feature branch.\n\nBREAKING CHANGE: it changed",
+			want: &Commit{
+				Type:        "feat",
+				Scope:       "api",
+				Description: "add thing",
+				MergeHeader: "Merge pull request #27 from wfscheper/feature-branch: feat(api)!: add thing",
+				Source:      "wfscheper/feature-branch",
+				Body:        "This squashes the feature branch.",
+				Footers:     []Footer{{Token: "BREAKING CHANGE", Value: "it changed"}},
+				Notes:       Notes{"BREAKING CHANGE": "it changed"},
+				IsBreaking:  true,
+			},
+		},
+	}
+
+	t.Parallel()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			commit, err := p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("p.Parse(%q) returned an unexpected error: %v", tt.input, err)
+			}
+			if got, want := commit, tt.want; !reflect.DeepEqual(got, want) {
+				t.Errorf("p.Parse(%q) returned %#v, want %#v", tt.input, got, want)
+			}
+		})
+	}
+}
+
+func TestParse_revert(t *testing.T) {
+	p, err := New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := `Revert "feat: add widget"` + "\n\nThis reverts commit abc123def456."
+	want := &Commit{
+		Reverts: Reverts{
+			"header": "feat: add widget",
+			"hash":   "abc123def456",
+		},
+	}
+
+	commit, err := p.Parse(input)
+	if err != nil {
+		t.Fatalf("p.Parse(%q) returned an unexpected error: %v", input, err)
+	}
+	if got := commit; !reflect.DeepEqual(got, want) {
+		t.Errorf("p.Parse(%q) returned %#v, want %#v", input, got, want)
+	}
+}
+
+func Test_parseHeader_multipleErrors(t *testing.T) {
+	p, err := New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "(ty(pe: description"
+	wantError := "illegal '(' character in type:1 col 0; illegal '(' character in scope:1 col 3"
+
+	_, err = p.parseHeader(input, 1)
+	if err == nil {
+		t.Fatalf("p.parseHeader(%q) returned no error, want %q", input, wantError)
+	}
+	if err.Error() != wantError {
+		t.Errorf("p.parseHeader(%q) returned error %q, want %q", input, err, wantError)
+	}
+	var perrs ParseErrors
+	if !errors.As(err, &perrs) {
+		t.Fatalf("p.parseHeader(%q) returned an error that is not a ParseErrors: %#v", input, err)
+	}
+	if len(perrs) != 2 {
+		t.Errorf("p.parseHeader(%q) returned %d errors, want 2", input, len(perrs))
+	}
+}
+
+func TestParse_malformedFooter(t *testing.T) {
+	p, err := New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "fix: patch thing\n\nThis fixes a thing.\n\nReviewed by: Z"
+	wantError := `footer token "Reviewed by" must not contain whitespace:5 col 11`
+
+	commit, err := p.Parse(input)
+	if commit != nil {
+		t.Errorf("p.Parse(%q) returned %#v, want nil", input, commit)
+	}
+	if err == nil || err.Error() != wantError {
+		t.Errorf("p.Parse(%q) returned error %v, want %q", input, err, wantError)
+	}
+}
+
+func TestParse_strictReferences(t *testing.T) {
+	p, err := New(&Config{StrictReferences: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "fix: patch thing\n\nThis fixes a thing.\n\nCloses #12\nHandles #34"
+	wantError := `unknown reference action "Handles":6 col 0`
+
+	commit, err := p.Parse(input)
+	if commit != nil {
+		t.Errorf("p.Parse(%q) returned %#v, want nil", input, commit)
+	}
+	if err == nil || err.Error() != wantError {
+		t.Errorf("p.Parse(%q) returned error %v, want %q", input, err, wantError)
+	}
+}
+
+func TestParseError_Format(t *testing.T) {
+	src := "feat(api(: add thing"
+	pe := ParseError{Line: 1, Char: 8, Message: "illegal '(' character in scope"}
+
+	want := "1:8: illegal '(' character in scope\n" +
+		"feat(api(: add thing\n" +
+		"        ^"
+	if got := pe.Format(src); got != want {
+		t.Errorf("pe.Format(%q) = %q, want %q", src, got, want)
+	}
+}
+
+func TestParseError_Format_negativeChar(t *testing.T) {
+	src := ""
+	pe := ParseError{Line: 1, Char: -1, Message: "commit type must be followed by a colon and a single space"}
+
+	want := "1:-1: commit type must be followed by a colon and a single space\n" +
+		"\n" +
+		"^"
+	if got := pe.Format(src); got != want {
+		t.Errorf("pe.Format(%q) = %q, want %q", src, got, want)
+	}
+}
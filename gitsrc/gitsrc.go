@@ -0,0 +1,159 @@
+// Copyright 2020 Walter Scheper
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitsrc adapts a go-git repository into a stream of parsed
+// conventional commits, so that convcom can be used as a changelog
+// generation building block.
+package gitsrc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	commit "github.com/wfscheper/convcom"
+)
+
+// Result pairs a parsed conventional commit with the git metadata of the
+// commit it came from.
+type Result struct {
+	*commit.Commit
+	Hash      plumbing.Hash
+	Author    object.Signature
+	Committer object.Signature
+}
+
+// Options controls how ParseRepo and ParseRange walk and parse a
+// repository's commits.
+type Options struct {
+	// Config is used to build the commit.Parser run on each commit
+	// message. If nil, commit.New is called with an empty commit.Config.
+	Config *commit.Config
+	// SkipMerges, when true, skips commits with more than one parent.
+	SkipMerges bool
+}
+
+// ParseRepo opens the git repository at repoPath and parses every commit
+// reachable from HEAD, oldest first.
+func ParseRepo(repoPath string, opts Options) ([]*Result, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open repository %q: %w", repoPath, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve HEAD of %q: %w", repoPath, err)
+	}
+	return parseRange(repo, plumbing.ZeroHash, head.Hash(), opts)
+}
+
+// ParseRange parses every commit reachable from to but not from from,
+// oldest first. If from is the zero hash, every ancestor of to is parsed.
+func ParseRange(repo *git.Repository, from, to plumbing.Hash) ([]*Result, error) {
+	return parseRange(repo, from, to, Options{})
+}
+
+func parseRange(repo *git.Repository, from, to plumbing.Hash, opts Options) ([]*Result, error) {
+	cfg := opts.Config
+	if nil == cfg {
+		cfg = &commit.Config{}
+	}
+	parser, err := commit.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build parser: %w", err)
+	}
+
+	excluded := map[plumbing.Hash]bool{}
+	if plumbing.ZeroHash != from {
+		if excluded, err = ancestors(repo, from); err != nil {
+			return nil, err
+		}
+	}
+
+	commits, err := repo.Log(&git.LogOptions{From: to, Order: git.LogOrderDFS})
+	if err != nil {
+		return nil, fmt.Errorf("cannot walk commits reachable from %s: %w", to, err)
+	}
+
+	var results []*Result
+	err = commits.ForEach(func(gc *object.Commit) error {
+		if excluded[gc.Hash] {
+			return nil
+		}
+		if opts.SkipMerges && gc.NumParents() > 1 {
+			return nil
+		}
+
+		c, err := parser.Parse(gc.Message)
+		if err != nil {
+			if nil != cfg.ErrorCallback {
+				line, char := errorPosition(err)
+				return cfg.ErrorCallback(fmt.Sprintf("cannot parse commit %s: %v", gc.Hash, err), line, char)
+			}
+			return fmt.Errorf("cannot parse commit %s: %w", gc.Hash, err)
+		}
+
+		results = append(results, &Result{
+			Commit:    c,
+			Hash:      gc.Hash,
+			Author:    gc.Author,
+			Committer: gc.Committer,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// repo.Log with LogOrderDFS walks depth first from to, newest first;
+	// reverse to get oldest first. With merges this is not a strict
+	// topological order: a commit is placed where the walk first reached it,
+	// so a commit on a merge's second-parent side can come before one of its
+	// own parents.
+	for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+		results[i], results[j] = results[j], results[i]
+	}
+
+	return results, nil
+}
+
+// ancestors returns the set of hashes reachable from hash, inclusive.
+func ancestors(repo *git.Repository, hash plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	iter, err := repo.Log(&git.LogOptions{From: hash})
+	if err != nil {
+		return nil, fmt.Errorf("cannot walk ancestors of %s: %w", hash, err)
+	}
+	seen := map[plumbing.Hash]bool{}
+	err = iter.ForEach(func(gc *object.Commit) error {
+		seen[gc.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot walk ancestors of %s: %w", hash, err)
+	}
+	return seen, nil
+}
+
+// errorPosition extracts the line and column from err if it is, or wraps, a
+// commit.ParseError.
+func errorPosition(err error) (line, char int) {
+	var pe commit.ParseError
+	if errors.As(err, &pe) {
+		return pe.Line, pe.Char
+	}
+	return 0, 0
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Walter Scheper
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitsrc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	commit "github.com/wfscheper/convcom"
+)
+
+// newTestRepo creates a repository at path containing one empty commit per
+// message, in order, and returns the hash of each commit.
+func newTestRepo(t *testing.T, path string, messages []string) []plumbing.Hash {
+	t.Helper()
+
+	repo, err := git.PlainInit(path, false)
+	if err != nil {
+		t.Fatalf("git.PlainInit(%q) returned an unexpected error: %v", path, err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("repo.Worktree() returned an unexpected error: %v", err)
+	}
+
+	sig := &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Unix(0, 0)}
+	hashes := make([]plumbing.Hash, 0, len(messages))
+	for _, msg := range messages {
+		hash, err := wt.Commit(msg, &git.CommitOptions{
+			Author:            sig,
+			AllowEmptyCommits: true,
+		})
+		if err != nil {
+			t.Fatalf("wt.Commit(%q) returned an unexpected error: %v", msg, err)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+func TestParseRepo(t *testing.T) {
+	dir := t.TempDir()
+	newTestRepo(t, dir, []string{
+		"feat: add a feature",
+		"fix: fix a bug\n\nThis fixes something important.\n\nCloses #42",
+		"this is not a conventional commit",
+	})
+
+	var skipped []string
+	results, err := ParseRepo(dir, Options{
+		Config: &commit.Config{
+			ErrorCallback: func(message string, line, char int) error {
+				skipped = append(skipped, message)
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ParseRepo(%q) returned an unexpected error: %v", dir, err)
+	}
+
+	if got, want := len(results), 2; got != want {
+		t.Fatalf("ParseRepo(%q) returned %d results, want %d", dir, got, want)
+	}
+	if got, want := results[0].Type, "feat"; got != want {
+		t.Errorf("results[0].Type = %q, want %q", got, want)
+	}
+	if got, want := results[1].Type, "fix"; got != want {
+		t.Errorf("results[1].Type = %q, want %q", got, want)
+	}
+	if got, want := results[1].References[0]["issue"], "42"; got != want {
+		t.Errorf("results[1].References[0][\"issue\"] = %q, want %q", got, want)
+	}
+	if got, want := len(skipped), 1; got != want {
+		t.Fatalf("ErrorCallback was called %d times, want %d", got, want)
+	}
+}
+
+func TestParseRepo_skipMerges(t *testing.T) {
+	dir := t.TempDir()
+	newTestRepo(t, dir, []string{
+		"feat: add a feature",
+		"fix: fix a bug",
+	})
+
+	results, err := ParseRepo(dir, Options{SkipMerges: true})
+	if err != nil {
+		t.Fatalf("ParseRepo(%q) returned an unexpected error: %v", dir, err)
+	}
+	if got, want := len(results), 2; got != want {
+		t.Fatalf("ParseRepo(%q) returned %d results, want %d", dir, got, want)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	dir := t.TempDir()
+	hashes := newTestRepo(t, dir, []string{
+		"feat: first",
+		"feat: second",
+		"feat: third",
+	})
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("git.PlainOpen(%q) returned an unexpected error: %v", dir, err)
+	}
+
+	results, err := ParseRange(repo, hashes[0], hashes[2])
+	if err != nil {
+		t.Fatalf("ParseRange() returned an unexpected error: %v", err)
+	}
+	if got, want := len(results), 2; got != want {
+		t.Fatalf("ParseRange() returned %d results, want %d", got, want)
+	}
+	if got, want := results[0].Description, "second"; got != want {
+		t.Errorf("results[0].Description = %q, want %q", got, want)
+	}
+	if got, want := results[1].Description, "third"; got != want {
+		t.Errorf("results[1].Description = %q, want %q", got, want)
+	}
+}
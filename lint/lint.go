@@ -0,0 +1,286 @@
+// Copyright 2020 Walter Scheper
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint validates parsed conventional commits against a project's
+// policy.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	commit "github.com/wfscheper/convcom"
+)
+
+// Severity is how serious a lint Issue is.
+type Severity string
+
+const (
+	// SeverityError indicates the commit violates the rules and should be
+	// rejected.
+	SeverityError Severity = "error"
+	// SeverityWarning indicates the commit is questionable but should not
+	// be rejected.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a single rule violation found by Linter.Check.
+type Issue struct {
+	// RuleID identifies which rule was violated, eg. "header-max-length".
+	RuleID string
+	// Severity is how serious the issue is.
+	Severity Severity
+	// Message describes the issue.
+	Message string
+	// Line and Char are the 1-based line and column of the issue. They
+	// mirror ParseError's Line and Char, except that ParseError's Char
+	// counts from 0.
+	Line, Char int
+}
+
+// Rules describes the policy a Linter enforces.
+type Rules struct {
+	// AllowedTypes restricts which commit types are acceptable. If empty,
+	// any type is allowed.
+	AllowedTypes []string `yaml:"allowedTypes" toml:"allowed_types"`
+	// AllowedScopes restricts which scopes are acceptable. If empty, any
+	// scope is allowed. Ignored when ScopePattern is set.
+	AllowedScopes []string `yaml:"allowedScopes" toml:"allowed_scopes"`
+	// ScopePattern is a regular expression the scope must match. Takes
+	// precedence over AllowedScopes.
+	ScopePattern string `yaml:"scopePattern" toml:"scope_pattern"`
+	// RequireScope requires every commit to declare a scope.
+	RequireScope bool `yaml:"requireScope" toml:"require_scope"`
+	// MaxHeaderLength is the maximum length, in runes, of the commit
+	// header. Zero means no limit.
+	MaxHeaderLength int `yaml:"maxHeaderLength" toml:"max_header_length"`
+	// MinDescriptionLength is the minimum length, in runes, of the
+	// description. Zero means no limit.
+	MinDescriptionLength int `yaml:"minDescriptionLength" toml:"min_description_length"`
+	// DescriptionCase constrains the case of the description's first
+	// letter: "lower", "sentence", or "any" (the default).
+	DescriptionCase string `yaml:"descriptionCase" toml:"description_case"`
+	// DisallowTrailingPunctuation rejects descriptions that end with
+	// punctuation, eg. a trailing period.
+	DisallowTrailingPunctuation bool `yaml:"disallowTrailingPunctuation" toml:"disallow_trailing_punctuation"`
+	// RequiredBreakingFooters lists footer tokens that must be present,
+	// case insensitively, whenever a commit is marked breaking.
+	//
+	// Default: []string{"BREAKING CHANGE"}
+	RequiredBreakingFooters []string `yaml:"requiredBreakingFooters" toml:"required_breaking_footers"`
+}
+
+var requiredBreakingFooters = []string{"BREAKING CHANGE"}
+
+// Linter checks parsed commits against a set of Rules.
+type Linter struct {
+	rules Rules
+}
+
+// New returns a new Linter that enforces rules.
+func New(rules Rules) *Linter {
+	if nil == rules.RequiredBreakingFooters {
+		rules.RequiredBreakingFooters = requiredBreakingFooters
+	}
+	return &Linter{rules: rules}
+}
+
+// Check reports every way c violates l's Rules.
+func (l *Linter) Check(c *commit.Commit) []Issue {
+	var issues []Issue
+	header := headerString(c)
+	headerLen := utf8.RuneCountInString(header)
+
+	if len(l.rules.AllowedTypes) > 0 && !contains(l.rules.AllowedTypes, c.Type) {
+		issues = append(issues, Issue{
+			RuleID:   "type",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("type %q is not one of the allowed types %v", c.Type, l.rules.AllowedTypes),
+			Line:     1,
+			Char:     1,
+		})
+	}
+
+	issues = append(issues, l.checkScope(c)...)
+
+	if l.rules.MaxHeaderLength > 0 && headerLen > l.rules.MaxHeaderLength {
+		issues = append(issues, Issue{
+			RuleID:   "header-max-length",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("header is %d characters, maximum is %d", headerLen, l.rules.MaxHeaderLength),
+			Line:     1,
+			Char:     l.rules.MaxHeaderLength + 1,
+		})
+	}
+
+	descLen := utf8.RuneCountInString(c.Description)
+	descStart := headerLen - descLen + 1
+	if l.rules.MinDescriptionLength > 0 && descLen < l.rules.MinDescriptionLength {
+		issues = append(issues, Issue{
+			RuleID:   "description-min-length",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("description is %d characters, minimum is %d", descLen, l.rules.MinDescriptionLength),
+			Line:     1,
+			Char:     descStart,
+		})
+	}
+
+	issues = append(issues, l.checkDescriptionCase(c, descStart)...)
+
+	if l.rules.DisallowTrailingPunctuation && "" != c.Description {
+		runes := []rune(c.Description)
+		if last := runes[len(runes)-1]; unicode.IsPunct(last) {
+			issues = append(issues, Issue{
+				RuleID:   "description-trailing-punctuation",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("description must not end with %q", string(last)),
+				Line:     1,
+				Char:     headerLen,
+			})
+		}
+	}
+
+	if c.IsBreaking {
+		for _, want := range l.rules.RequiredBreakingFooters {
+			if !hasFooter(c.Footers, want) {
+				issues = append(issues, Issue{
+					RuleID:   "breaking-footer-required",
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("breaking commit must have a %q footer", want),
+					Line:     1,
+					Char:     1,
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+func (l *Linter) checkScope(c *commit.Commit) []Issue {
+	charAfterType := utf8.RuneCountInString(c.Type) + 1
+	switch {
+	case l.rules.RequireScope && "" == c.Scope:
+		return []Issue{{
+			RuleID:   "scope-required",
+			Severity: SeverityError,
+			Message:  "commit must declare a scope",
+			Line:     1,
+			Char:     charAfterType,
+		}}
+	case "" == c.Scope:
+		return nil
+	case "" != l.rules.ScopePattern:
+		re, err := regexp.Compile(l.rules.ScopePattern)
+		if err != nil {
+			return []Issue{{
+				RuleID:   "scope",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("cannot compile ScopePattern /%s/: %v", l.rules.ScopePattern, err),
+				Line:     1,
+				Char:     charAfterType + 1,
+			}}
+		}
+		if !re.MatchString(c.Scope) {
+			return []Issue{{
+				RuleID:   "scope",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("scope %q does not match pattern /%s/", c.Scope, l.rules.ScopePattern),
+				Line:     1,
+				Char:     charAfterType + 1,
+			}}
+		}
+	case len(l.rules.AllowedScopes) > 0 && !contains(l.rules.AllowedScopes, c.Scope):
+		return []Issue{{
+			RuleID:   "scope",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("scope %q is not one of the allowed scopes %v", c.Scope, l.rules.AllowedScopes),
+			Line:     1,
+			Char:     charAfterType + 1,
+		}}
+	}
+	return nil
+}
+
+func (l *Linter) checkDescriptionCase(c *commit.Commit, descStart int) []Issue {
+	if "" == c.Description {
+		return nil
+	}
+	r := []rune(c.Description)[0]
+	switch l.rules.DescriptionCase {
+	case "lower":
+		if unicode.IsUpper(r) {
+			return []Issue{{
+				RuleID:   "description-case",
+				Severity: SeverityWarning,
+				Message:  "description must start with a lowercase letter",
+				Line:     1,
+				Char:     descStart,
+			}}
+		}
+	case "sentence":
+		if unicode.IsLower(r) {
+			return []Issue{{
+				RuleID:   "description-case",
+				Severity: SeverityWarning,
+				Message:  "description must start with an uppercase letter",
+				Line:     1,
+				Char:     descStart,
+			}}
+		}
+	}
+	return nil
+}
+
+// headerString reconstructs the commit header from its parsed parts.
+func headerString(c *commit.Commit) string {
+	if "" != c.Scope {
+		return fmt.Sprintf("%s(%s): %s", c.Type, c.Scope, c.Description)
+	}
+	return fmt.Sprintf("%s: %s", c.Type, c.Description)
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFooter(footers []commit.Footer, token string) bool {
+	for _, f := range footers {
+		if equalFold(f.Token, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalFold reports whether s and t are equal under Unicode case folding,
+// additionally treating the BREAKING-CHANGE git trailer token as the
+// BREAKING CHANGE note keyword.
+func equalFold(s, t string) bool {
+	normalize := func(token string) string {
+		if strings.EqualFold(token, "BREAKING-CHANGE") {
+			return "BREAKING CHANGE"
+		}
+		return token
+	}
+	return strings.EqualFold(normalize(s), normalize(t))
+}
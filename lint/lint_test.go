@@ -0,0 +1,143 @@
+// Copyright 2020 Walter Scheper
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"testing"
+
+	commit "github.com/wfscheper/convcom"
+)
+
+func ruleIDs(issues []Issue) []string {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.RuleID
+	}
+	return ids
+}
+
+func TestLinter_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   Rules
+		commit  *commit.Commit
+		wantIDs []string
+	}{
+		{
+			name:    "clean commit, no rules",
+			rules:   Rules{},
+			commit:  &commit.Commit{Type: "feat", Description: "add a thing"},
+			wantIDs: nil,
+		},
+		{
+			name:    "disallowed type",
+			rules:   Rules{AllowedTypes: []string{"feat", "fix"}},
+			commit:  &commit.Commit{Type: "chore", Description: "tidy up"},
+			wantIDs: []string{"type"},
+		},
+		{
+			name:    "missing required scope",
+			rules:   Rules{RequireScope: true},
+			commit:  &commit.Commit{Type: "feat", Description: "add a thing"},
+			wantIDs: []string{"scope-required"},
+		},
+		{
+			name:    "scope not in allowed list",
+			rules:   Rules{AllowedScopes: []string{"api", "cli"}},
+			commit:  &commit.Commit{Type: "feat", Scope: "docs", Description: "add a thing"},
+			wantIDs: []string{"scope"},
+		},
+		{
+			name:    "scope fails pattern",
+			rules:   Rules{ScopePattern: `^[a-z]+$`},
+			commit:  &commit.Commit{Type: "feat", Scope: "API", Description: "add a thing"},
+			wantIDs: []string{"scope"},
+		},
+		{
+			name:    "header too long",
+			rules:   Rules{MaxHeaderLength: 10},
+			commit:  &commit.Commit{Type: "feat", Description: "add a much longer thing"},
+			wantIDs: []string{"header-max-length"},
+		},
+		{
+			name:    "description too short",
+			rules:   Rules{MinDescriptionLength: 20},
+			commit:  &commit.Commit{Type: "feat", Description: "add a thing"},
+			wantIDs: []string{"description-min-length"},
+		},
+		{
+			name:    "description wrong case: lower",
+			rules:   Rules{DescriptionCase: "lower"},
+			commit:  &commit.Commit{Type: "feat", Description: "Add a thing"},
+			wantIDs: []string{"description-case"},
+		},
+		{
+			name:    "description wrong case: sentence",
+			rules:   Rules{DescriptionCase: "sentence"},
+			commit:  &commit.Commit{Type: "feat", Description: "add a thing"},
+			wantIDs: []string{"description-case"},
+		},
+		{
+			name:    "trailing punctuation",
+			rules:   Rules{DisallowTrailingPunctuation: true},
+			commit:  &commit.Commit{Type: "feat", Description: "add a thing."},
+			wantIDs: []string{"description-trailing-punctuation"},
+		},
+		{
+			name:  "breaking change missing footer",
+			rules: Rules{},
+			commit: &commit.Commit{
+				Type:        "feat",
+				Description: "drop old endpoint",
+				IsBreaking:  true,
+			},
+			wantIDs: []string{"breaking-footer-required"},
+		},
+		{
+			name:  "breaking change with footer",
+			rules: Rules{},
+			commit: &commit.Commit{
+				Type:        "feat",
+				Description: "drop old endpoint",
+				IsBreaking:  true,
+				Footers:     []commit.Footer{{Token: "BREAKING CHANGE", Value: "it's gone"}},
+			},
+			wantIDs: nil,
+		},
+		{
+			name:  "breaking change with BREAKING-CHANGE trailer token",
+			rules: Rules{},
+			commit: &commit.Commit{
+				Type:        "feat",
+				Description: "drop old endpoint",
+				IsBreaking:  true,
+				Footers:     []commit.Footer{{Token: "BREAKING-CHANGE", Value: "it's gone"}},
+			},
+			wantIDs: nil,
+		},
+	}
+
+	t.Parallel()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(tt.rules)
+			got := ruleIDs(l.Check(tt.commit))
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("l.Check(%#v) returned rule IDs %v, want %v", tt.commit, got, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Walter Scheper
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadRulesFile reads Rules from path, a YAML (.yaml/.yml) or TOML (.toml)
+// file, eg. a project's .convcom.yaml.
+func LoadRulesFile(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, fmt.Errorf("cannot read rules file %q: %w", path, err)
+	}
+
+	var rules Rules
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &rules)
+	case ".toml":
+		err = toml.Unmarshal(data, &rules)
+	default:
+		return Rules{}, fmt.Errorf("lint: unsupported rules file extension %q", ext)
+	}
+	if err != nil {
+		return Rules{}, fmt.Errorf("cannot parse rules file %q: %w", path, err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Walter Scheper
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadRulesFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		contents string
+		want     Rules
+	}{
+		{
+			name:     "yaml",
+			filename: ".convcom.yaml",
+			contents: "allowedTypes: [feat, fix]\nrequireScope: true\nmaxHeaderLength: 72\n",
+			want:     Rules{AllowedTypes: []string{"feat", "fix"}, RequireScope: true, MaxHeaderLength: 72},
+		},
+		{
+			name:     "toml",
+			filename: ".convcom.toml",
+			contents: "allowed_types = [\"feat\", \"fix\"]\nrequire_scope = true\nmax_header_length = 72\n",
+			want:     Rules{AllowedTypes: []string{"feat", "fix"}, RequireScope: true, MaxHeaderLength: 72},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, tt.filename)
+			if err := os.WriteFile(path, []byte(tt.contents), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadRulesFile(path)
+			if err != nil {
+				t.Fatalf("LoadRulesFile(%q) returned an unexpected error: %v", path, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadRulesFile(%q) = %#v, want %#v", path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRulesFile_unsupportedExtension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.ini")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadRulesFile(path); err == nil {
+		t.Errorf("LoadRulesFile(%q) returned no error, want an unsupported extension error", path)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Walter Scheper
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+)
+
+// ParseResult is a single commit read from a Parser.ParseStream.
+type ParseResult struct {
+	// Commit is the parsed commit. It is nil if Err is set.
+	Commit *Commit
+	// Err is the error, if any, encountered parsing this commit.
+	Err error
+	// Offset is the byte offset of the start of this commit in the stream.
+	Offset int64
+}
+
+// ParseStream reads commits from r, separated by sep, and sends a
+// ParseResult for each one on the returned channel. The channel is closed
+// once r is exhausted or a fatal error is hit.
+//
+// sep is a configurable boundary between commits, eg. "\x00" for the NUL
+// byte emitted by `git log -z`. ParseStream does not buffer the whole of
+// r in memory; it reads and parses one commit at a time.
+//
+// If a commit fails to parse and p.cfg.ErrorCallback is nil, the failure
+// is sent as a ParseResult and the stream stops. If ErrorCallback is set,
+// it is called with the parse error instead; a nil return continues the
+// stream past the malformed commit, and a non-nil return stops it, with
+// that error sent as the final ParseResult.
+func (p *Parser) ParseStream(r io.Reader, sep string) <-chan ParseResult {
+	return p.ParseStreamContext(context.Background(), r, sep)
+}
+
+// ParseStreamContext is ParseStream with a context for cancellation. Once
+// ctx is done, the stream stops and the channel is closed; a ParseResult
+// whose send was already waiting when ctx was done may still be delivered.
+func (p *Parser) ParseStreamContext(ctx context.Context, r io.Reader, sep string) <-chan ParseResult {
+	out := make(chan ParseResult)
+	go p.parseStream(ctx, r, sep, out)
+	return out
+}
+
+func (p *Parser) parseStream(ctx context.Context, r io.Reader, sep string, out chan<- ParseResult) {
+	defer close(out)
+
+	sepBytes := []byte(sep)
+	scanner := bufio.NewScanner(r)
+	scanner.Split(splitOnSeparator(sepBytes))
+
+	var b strings.Builder
+	var offset int64
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		start := offset
+		offset += int64(len(scanner.Bytes())) + int64(len(sepBytes))
+
+		b.Reset()
+		b.Write(scanner.Bytes())
+		c, err := p.Parse(b.String())
+		if err != nil {
+			if nil == p.cfg.ErrorCallback {
+				send(ctx, out, ParseResult{Err: err, Offset: start})
+				return
+			}
+			if cbErr := p.cfg.ErrorCallback(err.Error(), 0, 0); cbErr != nil {
+				send(ctx, out, ParseResult{Err: cbErr, Offset: start})
+				return
+			}
+			continue
+		}
+
+		if !send(ctx, out, ParseResult{Commit: c, Offset: start}) {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		send(ctx, out, ParseResult{Err: err, Offset: offset})
+	}
+}
+
+// send delivers result on out, returning false if ctx is done first.
+func send(ctx context.Context, out chan<- ParseResult, result ParseResult) bool {
+	select {
+	case out <- result:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// splitOnSeparator returns a bufio.SplitFunc that splits on occurrences of
+// sep, analogous to bufio.ScanLines but for an arbitrary boundary.
+func splitOnSeparator(sep []byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && 0 == len(data) {
+			return 0, nil, nil
+		}
+		if i := bytes.Index(data, sep); i >= 0 {
+			return i + len(sep), data[:i], nil
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
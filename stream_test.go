@@ -0,0 +1,170 @@
+// Copyright 2020 Walter Scheper
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStream(t *testing.T) {
+	input := "feat: add a thing\x00fix(api): fix a bug\x00docs: update readme\x00"
+	p, err := New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []ParseResult
+	for result := range p.ParseStream(strings.NewReader(input), "\x00") {
+		got = append(got, result)
+	}
+
+	want := []struct {
+		typ    string
+		scope  string
+		desc   string
+		offset int64
+	}{
+		{"feat", "", "add a thing", 0},
+		{"fix", "api", "fix a bug", 18},
+		{"docs", "", "update readme", 38},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("p.ParseStream(...) returned %d results, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		r := got[i]
+		if r.Err != nil {
+			t.Errorf("result %d: unexpected error %v", i, r.Err)
+			continue
+		}
+		if r.Commit.Type != w.typ || r.Commit.Scope != w.scope || r.Commit.Description != w.desc {
+			t.Errorf("result %d: got %#v, want Type=%q Scope=%q Description=%q", i, r.Commit, w.typ, w.scope, w.desc)
+		}
+		if r.Offset != w.offset {
+			t.Errorf("result %d: got Offset=%d, want %d", i, r.Offset, w.offset)
+		}
+	}
+}
+
+func TestParseStream_errorCallback(t *testing.T) {
+	input := "feat: add a thing\x00not a valid header\x00fix: fix a bug\x00"
+	var skipped []string
+	cfg := &Config{
+		ErrorCallback: func(message string, line, char int) error {
+			skipped = append(skipped, message)
+			return nil
+		},
+	}
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	for result := range p.ParseStream(strings.NewReader(input), "\x00") {
+		if result.Err != nil {
+			t.Errorf("unexpected error in result: %v", result.Err)
+			continue
+		}
+		types = append(types, result.Commit.Type)
+	}
+
+	if want := []string{"feat", "fix"}; len(types) != len(want) || types[0] != want[0] || types[1] != want[1] {
+		t.Errorf("got commit types %v, want %v", types, want)
+	}
+	if len(skipped) != 1 {
+		t.Errorf("ErrorCallback called %d times, want 1", len(skipped))
+	}
+}
+
+func TestParseStream_noErrorCallbackStops(t *testing.T) {
+	input := "feat: add a thing\x00not a valid header\x00fix: fix a bug\x00"
+	p, err := New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []ParseResult
+	for result := range p.ParseStream(strings.NewReader(input), "\x00") {
+		results = append(results, result)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].Err != nil {
+		t.Errorf("result 0: unexpected error %v", results[0].Err)
+	}
+	if results[1].Err == nil {
+		t.Errorf("result 1: expected an error, got none")
+	}
+}
+
+func TestParseStreamContext_cancel(t *testing.T) {
+	input := "feat: add a thing\x00fix: fix a bug\x00docs: update readme\x00"
+	p, err := New(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := p.ParseStreamContext(ctx, strings.NewReader(input), "\x00")
+
+	if _, ok := <-ch; !ok {
+		t.Fatal("expected at least one result before cancellation")
+	}
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			// a second result may or may not have been in flight when
+			// cancel() was called; either a value or a closed channel is
+			// acceptable here, so the value is discarded.
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after context cancellation")
+	}
+}
+
+func TestParseStream_fatalErrorCallback(t *testing.T) {
+	input := "feat: add a thing\x00not a valid header\x00fix: fix a bug\x00"
+	sentinel := errors.New("stop now")
+	cfg := &Config{
+		ErrorCallback: func(message string, line, char int) error {
+			return sentinel
+		},
+	}
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []ParseResult
+	for result := range p.ParseStream(strings.NewReader(input), "\x00") {
+		results = append(results, result)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if !errors.Is(results[1].Err, sentinel) {
+		t.Errorf("result 1: got error %v, want %v", results[1].Err, sentinel)
+	}
+}